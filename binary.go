@@ -0,0 +1,333 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// binary protocol magic bytes.
+const (
+	magicRequest  byte = 0x80
+	magicResponse byte = 0x81
+)
+
+// binary protocol opcodes, as defined by the memcached binary protocol spec.
+type opcode uint8
+
+const (
+	opGet       opcode = 0x00
+	opSet       opcode = 0x01
+	opAdd       opcode = 0x02
+	opReplace   opcode = 0x03
+	opDelete    opcode = 0x04
+	opIncrement opcode = 0x05
+	opDecrement opcode = 0x06
+	opFlush     opcode = 0x08
+	opAppend    opcode = 0x0e
+	opPrepend   opcode = 0x0f
+	opGetKQ     opcode = 0x0d
+	opTouch     opcode = 0x1c
+	opNoop      opcode = 0x0a
+)
+
+// binary protocol status codes.
+const (
+	statusOK            uint16 = 0x0000
+	statusKeyNotFound   uint16 = 0x0001
+	statusKeyExists     uint16 = 0x0002
+	statusItemNotStored uint16 = 0x0005
+)
+
+// headerSize is the fixed size, in bytes, of a binary protocol packet
+// header.
+const headerSize = 24
+
+// binaryHeader is the 24-byte header that precedes every binary protocol
+// request and response.
+type binaryHeader struct {
+	Magic        byte
+	Opcode       opcode
+	KeyLength    uint16
+	ExtrasLength uint8
+	DataType     uint8
+	Status       uint16 // vbucket id in requests, status in responses
+	TotalBody    uint32
+	Opaque       uint32
+	CAS          uint64
+}
+
+func (h binaryHeader) encode() []byte {
+	b := make([]byte, headerSize)
+	b[0] = h.Magic
+	b[1] = byte(h.Opcode)
+	binary.BigEndian.PutUint16(b[2:4], h.KeyLength)
+	b[4] = h.ExtrasLength
+	b[5] = h.DataType
+	binary.BigEndian.PutUint16(b[6:8], h.Status)
+	binary.BigEndian.PutUint32(b[8:12], h.TotalBody)
+	binary.BigEndian.PutUint32(b[12:16], h.Opaque)
+	binary.BigEndian.PutUint64(b[16:24], h.CAS)
+	return b
+}
+
+func decodeBinaryHeader(b []byte) binaryHeader {
+	return binaryHeader{
+		Magic:        b[0],
+		Opcode:       opcode(b[1]),
+		KeyLength:    binary.BigEndian.Uint16(b[2:4]),
+		ExtrasLength: b[4],
+		DataType:     b[5],
+		Status:       binary.BigEndian.Uint16(b[6:8]),
+		TotalBody:    binary.BigEndian.Uint32(b[8:12]),
+		Opaque:       binary.BigEndian.Uint32(b[12:16]),
+		CAS:          binary.BigEndian.Uint64(b[16:24]),
+	}
+}
+
+// statusErr translates a binary protocol status code into a memc error.
+func statusErr(status uint16) error {
+	switch status {
+	case statusOK:
+		return nil
+	case statusKeyNotFound:
+		return ErrCacheMiss
+	case statusKeyExists:
+		return ErrExists
+	case statusItemNotStored:
+		return ErrNotStored
+	default:
+		return fmt.Errorf("memc: binary protocol status 0x%04x", status)
+	}
+}
+
+// binaryResponse is the parsed result of a binary protocol round trip.
+type binaryResponse struct {
+	status uint16
+	extras []byte
+	key    []byte
+	value  []byte
+	cas    uint64
+}
+
+// binaryRoundTrip sends a single binary protocol request and reads back the
+// corresponding response.
+func (c *Client) binaryRoundTrip(ctx context.Context, addr string, op opcode, key string, extras, value []byte, cas uint64) (*binaryResponse, error) {
+	var resp *binaryResponse
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		head := binaryHeader{
+			Magic:        magicRequest,
+			Opcode:       op,
+			KeyLength:    uint16(len(key)),
+			ExtrasLength: uint8(len(extras)),
+			TotalBody:    uint32(len(extras) + len(key) + len(value)),
+			CAS:          cas,
+		}
+
+		if _, err := conn.rw.Write(head.encode()); err != nil {
+			return err
+		}
+		if _, err := conn.rw.Write(extras); err != nil {
+			return err
+		}
+		if _, err := conn.rw.WriteString(key); err != nil {
+			return err
+		}
+		if _, err := conn.rw.Write(value); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		rawHead := make([]byte, headerSize)
+		if _, err := io.ReadFull(conn.rw.Reader, rawHead); err != nil {
+			return err
+		}
+		respHead := decodeBinaryHeader(rawHead)
+
+		body := make([]byte, respHead.TotalBody)
+		if _, err := io.ReadFull(conn.rw.Reader, body); err != nil {
+			return err
+		}
+
+		resp = &binaryResponse{
+			status: respHead.Status,
+			cas:    respHead.CAS,
+			extras: body[:respHead.ExtrasLength],
+			key:    body[respHead.ExtrasLength : int(respHead.ExtrasLength)+int(respHead.KeyLength)],
+			value:  body[int(respHead.ExtrasLength)+int(respHead.KeyLength):],
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// binaryStore implements Set/Add/Replace/Cas over the binary protocol.
+func (c *Client) binaryStore(ctx context.Context, op opcode, addr, key string, flags uint32, exp int, data []byte, cas uint64) error {
+	extras := make([]byte, 8)
+	binary.BigEndian.PutUint32(extras[0:4], flags)
+	binary.BigEndian.PutUint32(extras[4:8], uint32(exp))
+
+	resp, err := c.binaryRoundTrip(ctx, addr, op, key, extras, data, cas)
+	if err != nil {
+		return err
+	}
+	return statusErr(resp.status)
+}
+
+// binaryFetch implements Get/GetCAS over the binary protocol.
+func (c *Client) binaryFetch(ctx context.Context, addr, key string) ([]byte, uint32, uint64, error) {
+	resp, err := c.binaryRoundTrip(ctx, addr, opGet, key, nil, nil, 0)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if err = statusErr(resp.status); err != nil {
+		return nil, 0, 0, err
+	}
+
+	var flags uint32
+	if len(resp.extras) == 4 {
+		flags = binary.BigEndian.Uint32(resp.extras)
+	}
+
+	return resp.value, flags, resp.cas, nil
+}
+
+// binaryFetchMulti implements GetMulti over the binary protocol: it
+// pipelines a quiet GETKQ for every key, followed by a terminating NOOP.
+// Quiet GETKQ misses get no response at all, which is how keys end up
+// absent from the result; hits respond with the key (GETKQ's "K") so the
+// response can be matched back up. The batch ends on the NOOP response
+// rather than on a key match against the last key, since a real memcached
+// server doesn't echo the key (or anything else identifying the request)
+// on a miss response - ending on the last key's own response would hang
+// forever if that particular key happened to miss.
+func (c *Client) binaryFetchMulti(ctx context.Context, addr string, keys []string) (map[string][]byte, map[string]uint32, error) {
+	data := make(map[string][]byte)
+	flags := make(map[string]uint32)
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		for _, key := range keys {
+			head := binaryHeader{
+				Magic:     magicRequest,
+				Opcode:    opGetKQ,
+				KeyLength: uint16(len(key)),
+				TotalBody: uint32(len(key)),
+			}
+			if _, err := conn.rw.Write(head.encode()); err != nil {
+				return err
+			}
+			if _, err := conn.rw.WriteString(key); err != nil {
+				return err
+			}
+		}
+
+		noop := binaryHeader{Magic: magicRequest, Opcode: opNoop}
+		if _, err := conn.rw.Write(noop.encode()); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		for {
+			rawHead := make([]byte, headerSize)
+			if _, err := io.ReadFull(conn.rw.Reader, rawHead); err != nil {
+				return err
+			}
+			respHead := decodeBinaryHeader(rawHead)
+
+			body := make([]byte, respHead.TotalBody)
+			if _, err := io.ReadFull(conn.rw.Reader, body); err != nil {
+				return err
+			}
+
+			if respHead.Opcode == opNoop {
+				return nil
+			}
+
+			respKey := string(body[respHead.ExtrasLength : int(respHead.ExtrasLength)+int(respHead.KeyLength)])
+
+			if respHead.Status == statusOK {
+				var f uint32
+				if respHead.ExtrasLength == 4 {
+					f = binary.BigEndian.Uint32(body[:4])
+				}
+				data[respKey] = body[int(respHead.ExtrasLength)+int(respHead.KeyLength):]
+				flags[respKey] = f
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, flags, nil
+}
+
+// binaryAppendPrepend implements Append/Prepend over the binary protocol.
+func (c *Client) binaryAppendPrepend(ctx context.Context, op opcode, addr, key string, data []byte) error {
+	resp, err := c.binaryRoundTrip(ctx, addr, op, key, nil, data, 0)
+	if err != nil {
+		return err
+	}
+	return statusErr(resp.status)
+}
+
+// binaryDelete implements Delete over the binary protocol.
+func (c *Client) binaryDelete(ctx context.Context, addr, key string) error {
+	resp, err := c.binaryRoundTrip(ctx, addr, opDelete, key, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return statusErr(resp.status)
+}
+
+// binaryIncrDecr implements Increment/Decrement over the binary protocol.
+func (c *Client) binaryIncrDecr(ctx context.Context, op opcode, addr, key string, delta, initial uint64, exp int) (uint64, error) {
+	extras := make([]byte, 20)
+	binary.BigEndian.PutUint64(extras[0:8], delta)
+	binary.BigEndian.PutUint64(extras[8:16], initial)
+	binary.BigEndian.PutUint32(extras[16:20], uint32(exp))
+
+	resp, err := c.binaryRoundTrip(ctx, addr, op, key, extras, nil, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err = statusErr(resp.status); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(resp.value), nil
+}
+
+// binaryTouch implements Touch over the binary protocol.
+func (c *Client) binaryTouch(ctx context.Context, addr, key string, exp int) error {
+	extras := make([]byte, 4)
+	binary.BigEndian.PutUint32(extras, uint32(exp))
+
+	resp, err := c.binaryRoundTrip(ctx, addr, opTouch, key, extras, nil, 0)
+	if err != nil {
+		return err
+	}
+	return statusErr(resp.status)
+}
+
+// binaryFlushAll implements FlushAll over the binary protocol.
+func (c *Client) binaryFlushAll(ctx context.Context, addr string) error {
+	resp, err := c.binaryRoundTrip(ctx, addr, opFlush, "", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return statusErr(resp.status)
+}
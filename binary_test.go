@@ -0,0 +1,54 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_binaryHeader_encode_decode(t *testing.T) {
+	t.Parallel()
+
+	head := binaryHeader{
+		Magic:        magicRequest,
+		Opcode:       opSet,
+		KeyLength:    5,
+		ExtrasLength: 8,
+		DataType:     0,
+		Status:       0,
+		TotalBody:    20,
+		Opaque:       42,
+		CAS:          123456789,
+	}
+
+	b := head.encode()
+	must.SliceLen(t, headerSize, b)
+	must.Eq(t, head, decodeBinaryHeader(b))
+}
+
+func Test_statusErr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		must.NoError(t, statusErr(statusOK))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		must.ErrorIs(t, statusErr(statusKeyNotFound), ErrCacheMiss)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		must.ErrorIs(t, statusErr(statusKeyExists), ErrExists)
+	})
+
+	t.Run("not stored", func(t *testing.T) {
+		must.ErrorIs(t, statusErr(statusItemNotStored), ErrNotStored)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		must.Error(t, statusErr(0x0099))
+	})
+}
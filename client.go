@@ -0,0 +1,223 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package memc implements a client for the memcached protocol.
+package memc
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDialTimeout  = 2 * time.Second
+	defaultMaxIdleConns = 2
+	defaultOpTimeout    = 5 * time.Second
+)
+
+// Client is a memcached client, optionally sharding across multiple
+// servers. A Client is safe for concurrent use by multiple goroutines.
+type Client struct {
+	mu    sync.Mutex
+	pools map[string]*pool
+
+	servers []string
+	health  *serverHealth
+	hasher  Hasher
+
+	ringMu      sync.Mutex
+	ring        *ketamaRing
+	ringServers []string
+
+	timeout      time.Duration
+	opTimeout    time.Duration
+	expiration   time.Duration
+	maxIdleConns int
+	binary       bool
+	codec        Codec
+}
+
+// connection wraps a net.Conn with the buffered reader/writer used to speak
+// the memcached wire protocols.
+type connection struct {
+	nc net.Conn
+	rw *bufio.ReadWriter
+}
+
+// Option configures a Client created via New.
+type Option func(*Client)
+
+// SetDialTimeout sets the timeout used when establishing new connections to
+// memcached servers. The default is 2 seconds.
+func SetDialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// SetDefaultTTL sets the default expiration applied to Set operations that
+// do not specify a TTL option of their own. The default is no expiration.
+// Durations of 30 days or more are sent to memcached as an absolute Unix
+// timestamp rather than a relative number of seconds - see seconds.
+func SetDefaultTTL(d time.Duration) Option {
+	return func(c *Client) {
+		c.expiration = d
+	}
+}
+
+// UseBinaryProtocol switches the wire format used to talk to memcached
+// servers. By default the Client speaks the original line-oriented text
+// protocol; passing true switches to the opcode-framed binary protocol.
+func UseBinaryProtocol(enabled bool) Option {
+	return func(c *Client) {
+		c.binary = enabled
+	}
+}
+
+// SetHasher sets the Hasher used to place keys on the consistent-hash ring
+// (or, when h is produced by Modulo, to shard by plain modulo instead). The
+// default is CRC32Hasher.
+func SetHasher(h Hasher) Option {
+	return func(c *Client) {
+		c.hasher = h
+	}
+}
+
+// SetMaxIdleConns sets how many idle connections are kept open per server
+// for reuse. The default is 2.
+func SetMaxIdleConns(n int) Option {
+	return func(c *Client) {
+		c.maxIdleConns = n
+	}
+}
+
+// SetOpTimeout sets the deadline applied to the socket read/write of an
+// individual operation whose context carries no deadline of its own, which
+// includes every operation made through the non-context functions (they
+// use context.Background). Establishing a new connection is not covered by
+// it; that is bounded by SetDialTimeout. The default is 5 seconds.
+func SetOpTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.opTimeout = d
+	}
+}
+
+// SetCodec sets the Codec used to marshal and unmarshal values that aren't
+// one of the fixed-width integer types, a string, or a []byte - those
+// always go through an internal fast-path codec, regardless of this
+// setting, so that the wire format for those types never changes no matter
+// which Codec is configured. The default is GobCodec, which affects the
+// wire format of every other type (structs, maps, and so on).
+func SetCodec(codec Codec) Option {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// New creates a Client that talks to the given set of memcached servers,
+// sharding keys across them via a Ketama consistent-hash ring.
+func New(servers []string, opts ...Option) *Client {
+	c := &Client{
+		servers:      servers,
+		pools:        make(map[string]*pool),
+		health:       newServerHealth(),
+		hasher:       CRC32Hasher(),
+		timeout:      defaultDialTimeout,
+		opTimeout:    defaultOpTimeout,
+		maxIdleConns: defaultMaxIdleConns,
+		codec:        GobCodec{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Close releases any connections held open by c.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for addr, p := range c.pools {
+		p.mu.Lock()
+		for _, conn := range p.idle {
+			if cerr := conn.nc.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		p.idle = nil
+		p.mu.Unlock()
+		delete(c.pools, addr)
+	}
+	return err
+}
+
+// activeServers returns the configured servers, minus any currently
+// ejected by health tracking. If every server looks unhealthy, the full
+// list is returned rather than refusing all traffic.
+func (c *Client) activeServers() []string {
+	if len(c.servers) <= 1 {
+		return c.servers
+	}
+
+	active := make([]string, 0, len(c.servers))
+	for _, s := range c.servers {
+		if !c.health.ejected(s) {
+			active = append(active, s)
+		}
+	}
+	if len(active) == 0 {
+		return c.servers
+	}
+	return active
+}
+
+// ketamaRingFor returns the ring built over servers, rebuilding it only
+// when the active server set has changed since the last build.
+func (c *Client) ketamaRingFor(servers []string) *ketamaRing {
+	c.ringMu.Lock()
+	defer c.ringMu.Unlock()
+
+	if c.ring != nil && sameServers(c.ringServers, servers) {
+		return c.ring
+	}
+
+	c.ring = buildKetamaRing(servers)
+	c.ringServers = servers
+	return c.ring
+}
+
+func sameServers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pick selects which configured server owns key.
+func (c *Client) pick(key string) (string, error) {
+	servers := c.activeServers()
+	if len(servers) == 0 {
+		return "", ErrNoServers
+	}
+	if len(servers) == 1 {
+		return servers[0], nil
+	}
+
+	if m, ok := c.hasher.(moduloHasher); ok {
+		h := m.Hash(key)
+		return servers[h%uint32(len(servers))], nil
+	}
+
+	return c.ketamaRingFor(servers).pick(key, c.hasher), nil
+}
@@ -46,17 +46,41 @@ func Test_seconds(t *testing.T) {
 		must.Eq(t, 4, s)
 	})
 
-	t.Run("month", func(t *testing.T) {
+	t.Run("just under a month", func(t *testing.T) {
 		ttl := 30 * 24 * time.Hour
 		fix := ttl - (1 * time.Second)
 		s, err := seconds(fix)
 		must.NoError(t, err)
 		must.Eq(t, 2591999, s)
+	})
+
+	t.Run("30d", func(t *testing.T) {
+		assertAbsoluteTimestamp(t, 30*24*time.Hour)
+	})
+
+	t.Run("60d", func(t *testing.T) {
+		assertAbsoluteTimestamp(t, 60*24*time.Hour)
+	})
 
-		// TODO support for 1+ month values
+	t.Run("1y", func(t *testing.T) {
+		assertAbsoluteTimestamp(t, 365*24*time.Hour)
 	})
 }
 
+// assertAbsoluteTimestamp asserts seconds(ttl) encodes ttl as the absolute
+// Unix timestamp ttl from now, rather than a relative number of seconds.
+func assertAbsoluteTimestamp(t *testing.T, ttl time.Duration) {
+	t.Helper()
+
+	before := time.Now().Add(ttl).Unix()
+	s, err := seconds(ttl)
+	must.NoError(t, err)
+	after := time.Now().Add(ttl).Unix()
+
+	must.GreaterEq(t, int64(s), before)
+	must.LessEq(t, int64(s), after)
+}
+
 func Test_check(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,105 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+)
+
+// encode turns v into the bytes that will be stored as the value of a
+// memcached item. Fixed-width integer types and the raw []byte/string types
+// are encoded directly (little endian, for integers) to keep the wire
+// representation as small as possible; everything else falls back to
+// encoding/gob.
+func encode(v any) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	case int8:
+		return []byte{byte(t)}, nil
+	case uint8:
+		return []byte{t}, nil
+	case int16:
+		b := make([]byte, 2)
+		binary.LittleEndian.PutUint16(b, uint16(t))
+		return b, nil
+	case uint16:
+		b := make([]byte, 2)
+		binary.LittleEndian.PutUint16(b, t)
+		return b, nil
+	case int32:
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(t))
+		return b, nil
+	case uint32:
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, t)
+		return b, nil
+	case int64:
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(t))
+		return b, nil
+	case uint64:
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, t)
+		return b, nil
+	case int:
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(t))
+		return b, nil
+	case uint:
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(t))
+		return b, nil
+	default:
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// decode reverses encode, producing a value of type T from the bytes stored
+// for a memcached item.
+func decode[T any](data []byte) (T, error) {
+	var out T
+
+	switch p := any(&out).(type) {
+	case *[]byte:
+		*p = append([]byte(nil), data...)
+	case *string:
+		*p = string(data)
+	case *int8:
+		*p = int8(data[0])
+	case *uint8:
+		*p = data[0]
+	case *int16:
+		*p = int16(binary.LittleEndian.Uint16(data))
+	case *uint16:
+		*p = binary.LittleEndian.Uint16(data)
+	case *int32:
+		*p = int32(binary.LittleEndian.Uint32(data))
+	case *uint32:
+		*p = binary.LittleEndian.Uint32(data)
+	case *int64:
+		*p = int64(binary.LittleEndian.Uint64(data))
+	case *uint64:
+		*p = binary.LittleEndian.Uint64(data)
+	case *int:
+		*p = int(binary.LittleEndian.Uint64(data))
+	case *uint:
+		*p = uint(binary.LittleEndian.Uint64(data))
+	default:
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+			return out, err
+		}
+	}
+
+	return out, nil
+}
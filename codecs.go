@@ -0,0 +1,120 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+)
+
+// Codec marshals and unmarshals memcached values. Built-in implementations
+// are GobCodec (the default), JSONCodec, and RawCodec; a Client's codec is
+// set with SetCodec and may be overridden per call with WithCodec. A
+// caller-supplied Codec is identified on the wire by a hash of its type
+// name (see codecTag) rather than a value stored in the flags field by
+// the interface itself, so two distinct custom Codecs collide only in the
+// unlikely event their type names hash to the same byte.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// codec tags are stored in the low byte of a Set item's flags, so Get can
+// tell whether the stored value is compatible with the codec it's about to
+// use to decode it.
+const (
+	tagInternal uint8 = 1 // fixed-width integers and raw string/[]byte values, handled without a Codec
+	tagGob      uint8 = 2
+	tagJSON     uint8 = 3
+	tagRaw      uint8 = 4
+
+	// firstCustomTag is the start of the tag space left over for
+	// customCodecTag to hash caller-supplied Codecs into.
+	firstCustomTag uint8 = 5
+)
+
+// codecTag identifies which tag a Set call should record for codec.
+// Built-in codecs get their own fixed tag; anything else is tagged with a
+// hash of its concrete type, so that Get can tell two different custom
+// Codecs apart rather than treating them as interchangeable.
+func codecTag(codec Codec) uint8 {
+	switch codec.(type) {
+	case GobCodec:
+		return tagGob
+	case JSONCodec:
+		return tagJSON
+	case RawCodec:
+		return tagRaw
+	default:
+		return customCodecTag(codec)
+	}
+}
+
+// customCodecTag hashes codec's concrete type name into the tag space left
+// over by the built-in codecs. It is a single byte, so it cannot guarantee
+// two unrelated custom Codecs never collide - only that they usually
+// won't - which is a real (if unlikely) limitation of a one-byte tag.
+func customCodecTag(codec Codec) uint8 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprintf("%T", codec)))
+	span := uint32(256 - int(firstCustomTag))
+	return firstCustomTag + uint8(h.Sum32()%span)
+}
+
+// GobCodec marshals values with encoding/gob. It is the default Codec used
+// by New.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// JSONCodec marshals values with encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// RawCodec passes []byte and string values through unmodified. It errors
+// on any other type.
+type RawCodec struct{}
+
+func (RawCodec) Marshal(v any) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	default:
+		return nil, fmt.Errorf("memc: RawCodec cannot marshal %T", v)
+	}
+}
+
+func (RawCodec) Unmarshal(data []byte, v any) error {
+	switch p := v.(type) {
+	case *[]byte:
+		*p = append([]byte(nil), data...)
+	case *string:
+		*p = string(data)
+	default:
+		return fmt.Errorf("memc: RawCodec cannot unmarshal into %T", v)
+	}
+	return nil
+}
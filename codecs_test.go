@@ -0,0 +1,96 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+type customCodecA struct{}
+
+func (customCodecA) Marshal(v any) ([]byte, error)      { return GobCodec{}.Marshal(v) }
+func (customCodecA) Unmarshal(data []byte, v any) error { return GobCodec{}.Unmarshal(data, v) }
+
+type customCodecB struct{}
+
+func (customCodecB) Marshal(v any) ([]byte, error)      { return GobCodec{}.Marshal(v) }
+func (customCodecB) Unmarshal(data []byte, v any) error { return GobCodec{}.Unmarshal(data, v) }
+
+func Test_codecTag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("built-ins get their fixed tag", func(t *testing.T) {
+		must.Eq(t, tagGob, codecTag(GobCodec{}))
+		must.Eq(t, tagJSON, codecTag(JSONCodec{}))
+		must.Eq(t, tagRaw, codecTag(RawCodec{}))
+	})
+
+	t.Run("stable across calls", func(t *testing.T) {
+		must.Eq(t, codecTag(customCodecA{}), codecTag(customCodecA{}))
+	})
+
+	t.Run("distinguishes different custom codecs", func(t *testing.T) {
+		must.NotEq(t, codecTag(customCodecA{}), codecTag(customCodecB{}))
+	})
+}
+
+func Test_RawCodec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal string", func(t *testing.T) {
+		b, err := RawCodec{}.Marshal("hello")
+		must.NoError(t, err)
+		must.Eq(t, []byte("hello"), b)
+	})
+
+	t.Run("marshal unsupported type", func(t *testing.T) {
+		_, err := RawCodec{}.Marshal(42)
+		must.Error(t, err)
+	})
+
+	t.Run("unmarshal into string", func(t *testing.T) {
+		var s string
+		must.NoError(t, RawCodec{}.Unmarshal([]byte("hello"), &s))
+		must.Eq(t, "hello", s)
+	})
+
+	t.Run("unmarshal unsupported type", func(t *testing.T) {
+		var n int
+		must.Error(t, RawCodec{}.Unmarshal([]byte("hello"), &n))
+	})
+}
+
+func Test_isFastPath(t *testing.T) {
+	t.Parallel()
+
+	must.True(t, isFastPath(42))
+	must.True(t, isFastPath("str"))
+	must.True(t, isFastPath([]byte{1, 2}))
+	must.False(t, isFastPath(person{}))
+}
+
+func Test_marshalValue_unmarshalValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("string bypasses the configured codec", func(t *testing.T) {
+		data, tag, err := marshalValue("myvalue", JSONCodec{})
+		must.NoError(t, err)
+		must.Eq(t, tagInternal, tag)
+		must.Eq(t, []byte("myvalue"), data)
+
+		v, err := unmarshalValue[string](data, tag, JSONCodec{})
+		must.NoError(t, err)
+		must.Eq(t, "myvalue", v)
+	})
+
+	t.Run("mismatched codec tag is rejected", func(t *testing.T) {
+		data, tag, err := marshalValue(person{Name: "bob", Age: 32}, JSONCodec{})
+		must.NoError(t, err)
+
+		_, err = unmarshalValue[person](data, tag, GobCodec{})
+		must.ErrorIs(t, err, ErrCodecMismatch)
+	})
+}
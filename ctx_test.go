@@ -0,0 +1,56 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_Client_deadline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uses the context's own deadline when it has one", func(t *testing.T) {
+		c := New(nil, SetOpTimeout(10*time.Second))
+
+		want := time.Now().Add(1 * time.Second)
+		ctx, cancel := context.WithDeadline(context.Background(), want)
+		defer cancel()
+
+		must.Eq(t, want, c.deadline(ctx))
+	})
+
+	t.Run("falls back to opTimeout otherwise", func(t *testing.T) {
+		c := New(nil, SetOpTimeout(3*time.Second))
+
+		before := time.Now().Add(3 * time.Second)
+		got := c.deadline(context.Background())
+		after := time.Now().Add(3 * time.Second)
+
+		must.GreaterEq(t, got.Unix(), before.Unix())
+		must.LessEq(t, got.Unix(), after.Unix())
+	})
+}
+
+func Test_checkout_honors_canceled_context(t *testing.T) {
+	t.Parallel()
+
+	c := New([]string{"127.0.0.1:1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.checkout(ctx, "127.0.0.1:1")
+	must.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_SetOpTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil, SetOpTimeout(7*time.Second))
+	must.Eq(t, 7*time.Second, c.opTimeout)
+}
@@ -149,3 +149,249 @@ func Test_Get_miss(t *testing.T) {
 	_, err := Get[string](c, "missing")
 	must.ErrorIs(t, err, ErrCacheMiss)
 }
+
+// TestE2E_CommandSurface covers Add, Replace, Append, Prepend, Delete,
+// Increment, Decrement, Touch, FlushAll, and GetCAS/SetCAS against a real
+// memcached process, for both the text and binary protocols.
+func TestE2E_CommandSurface(t *testing.T) {
+	t.Parallel()
+
+	for _, binary := range []bool{false, true} {
+		binary := binary
+		name := "text"
+		if binary {
+			name = "binary"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			address, done := launchTCP(t, nil)
+			t.Cleanup(done)
+
+			c := New([]string{address}, UseBinaryProtocol(binary))
+			defer ignore.Close(c)
+
+			t.Run("Add and Replace", func(t *testing.T) {
+				const key = "surface-add-replace"
+
+				must.NoError(t, Add(c, key, "first"))
+				must.ErrorIs(t, Add(c, key, "again"), ErrNotStored)
+
+				must.NoError(t, Replace(c, key, "second"))
+				v, err := Get[string](c, key)
+				must.NoError(t, err)
+				must.Eq(t, "second", v)
+
+				must.ErrorIs(t, Replace(c, "surface-add-replace-missing", "third"), ErrNotStored)
+			})
+
+			t.Run("Append and Prepend", func(t *testing.T) {
+				const key = "surface-append-prepend"
+
+				must.NoError(t, Set(c, key, "middle"))
+				must.NoError(t, Append(c, key, "-end"))
+				must.NoError(t, Prepend(c, key, "start-"))
+
+				v, err := Get[string](c, key)
+				must.NoError(t, err)
+				must.Eq(t, "start-middle-end", v)
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				const key = "surface-delete"
+
+				must.NoError(t, Set(c, key, "value"))
+				must.NoError(t, Delete(c, key))
+				must.ErrorIs(t, Delete(c, key), ErrCacheMiss)
+
+				_, err := Get[string](c, key)
+				must.ErrorIs(t, err, ErrCacheMiss)
+			})
+
+			t.Run("Increment and Decrement", func(t *testing.T) {
+				const key = "surface-counter"
+
+				must.NoError(t, Set(c, key, "10"))
+
+				n, err := Increment(c, key, 5)
+				must.NoError(t, err)
+				must.Eq(t, uint64(15), n)
+
+				n, err = Decrement(c, key, 3)
+				must.NoError(t, err)
+				must.Eq(t, uint64(12), n)
+
+				_, err = Increment(c, "surface-counter-missing", 1)
+				must.ErrorIs(t, err, ErrCacheMiss)
+			})
+
+			t.Run("Touch", func(t *testing.T) {
+				const key = "surface-touch"
+
+				must.NoError(t, Set(c, key, "value"))
+				must.NoError(t, Touch(c, key, 1*time.Hour))
+				must.ErrorIs(t, Touch(c, "surface-touch-missing", 1*time.Hour), ErrCacheMiss)
+			})
+
+			t.Run("GetCAS and SetCAS", func(t *testing.T) {
+				const key = "surface-cas"
+
+				must.NoError(t, Set(c, key, "original"))
+
+				v, cas, err := GetCAS[string](c, key)
+				must.NoError(t, err)
+				must.Eq(t, "original", v)
+
+				must.NoError(t, SetCAS(c, key, "updated", cas))
+				must.ErrorIs(t, SetCAS(c, key, "stale", cas), ErrExists)
+
+				v, err = Get[string](c, key)
+				must.NoError(t, err)
+				must.Eq(t, "updated", v)
+			})
+
+			// FlushAll invalidates every item on the server, so it runs
+			// last among these subtests - they aren't t.Parallel(), so Go
+			// runs them in this declared order.
+			t.Run("FlushAll", func(t *testing.T) {
+				const key = "surface-flush"
+
+				must.NoError(t, Set(c, key, "value"))
+				must.NoError(t, FlushAll(c))
+
+				_, err := Get[string](c, key)
+				must.ErrorIs(t, err, ErrCacheMiss)
+			})
+		})
+	}
+}
+
+// Test_GetMulti covers a mix of present and missing keys, with a missing
+// key placed last in the batch deliberately: that ordering is exactly what
+// used to hang binaryFetchMulti, since a real memcached server's miss
+// response never echoes back the key or opaque the client was waiting on.
+func Test_GetMulti(t *testing.T) {
+	t.Parallel()
+
+	for _, binary := range []bool{false, true} {
+		binary := binary
+		name := "text"
+		if binary {
+			name = "binary"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			address, done := launchTCP(t, nil)
+			t.Cleanup(done)
+
+			c := New([]string{address}, UseBinaryProtocol(binary))
+			defer ignore.Close(c)
+
+			must.NoError(t, Set(c, "multi-hit-1", "one"))
+			must.NoError(t, Set(c, "multi-hit-2", "two"))
+
+			got, err := GetMulti[string](c, []string{"multi-hit-1", "multi-hit-2", "multi-miss"})
+			must.NoError(t, err)
+
+			must.Eq(t, map[string]string{
+				"multi-hit-1": "one",
+				"multi-hit-2": "two",
+			}, got)
+		})
+	}
+}
+
+// launchTCPBench is launchTCP's benchmark counterpart - *testing.B does not
+// satisfy the *testing.T the must/skip helpers expect, so it drives the
+// memcached subprocess and readiness check directly.
+func launchTCPBench(b *testing.B, args []string) (string, func()) {
+	b.Helper()
+
+	if _, err := exec.LookPath(executable); err != nil {
+		b.Skip("memcached not found on $PATH")
+	}
+
+	port := ports.One()
+	address := fmt.Sprintf("localhost:%d", port)
+	args = append(args, "-l", address)
+
+	ctx, cancel := xtc.Cancelable()
+	cmd := exec.CommandContext(ctx, executable, args...)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		b.Fatalf("start memcached: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		conn, derr := net.Dial("tcp", address)
+		if derr == nil {
+			_ = conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			cancel()
+			b.Fatalf("memcached did not start listening: %v", derr)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return address, cancel
+}
+
+// BenchmarkGetMulti and BenchmarkGetLoop both fetch the same set of keys,
+// the former with a single pipelined GetMulti and the latter with one Get
+// per key, to demonstrate the round-trip savings GetMulti gives cache
+// warmup and fan-out read patterns.
+func BenchmarkGetMulti(b *testing.B) {
+	address, done := launchTCPBench(b, nil)
+	defer done()
+
+	c := New([]string{address})
+	defer ignore.Close(c)
+
+	const n = 100
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("bench-multi-%d", i)
+		if err := Set(c, keys[i], i); err != nil {
+			b.Fatalf("seed: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetMulti[int](c, keys); err != nil {
+			b.Fatalf("GetMulti: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetLoop(b *testing.B) {
+	address, done := launchTCPBench(b, nil)
+	defer done()
+
+	c := New([]string{address})
+	defer ignore.Close(c)
+
+	const n = 100
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("bench-loop-%d", i)
+		if err := Set(c, keys[i], i); err != nil {
+			b.Fatalf("seed: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			if _, err := Get[int](c, key); err != nil {
+				b.Fatalf("Get: %v", err)
+			}
+		}
+	}
+}
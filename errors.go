@@ -0,0 +1,36 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import "errors"
+
+var (
+	// ErrKeyNotValid is returned when a key is empty, too long, or contains
+	// whitespace or control characters not permitted by the memcached
+	// protocol.
+	ErrKeyNotValid = errors.New("memc: key not valid")
+
+	// ErrExpiration is returned when a TTL duration cannot be represented
+	// as a whole number of seconds.
+	ErrExpiration = errors.New("memc: expiration not valid")
+
+	// ErrCacheMiss is returned when a Get (or similar retrieval operation)
+	// finds no value stored for the given key.
+	ErrCacheMiss = errors.New("memc: cache miss")
+
+	// ErrNotStored is returned when Add fails because the key already
+	// exists, or Replace fails because the key does not exist.
+	ErrNotStored = errors.New("memc: not stored")
+
+	// ErrExists is returned when a CAS operation is rejected because the
+	// item has been modified since it was last fetched.
+	ErrExists = errors.New("memc: exists")
+
+	// ErrNoServers is returned when a Client has no servers configured.
+	ErrNoServers = errors.New("memc: no servers configured")
+
+	// ErrCodecMismatch is returned by Get when the item's stored codec tag
+	// does not match the codec being used to decode it.
+	ErrCodecMismatch = errors.New("memc: codec mismatch")
+)
@@ -0,0 +1,53 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"hash/crc32"
+	"hash/fnv"
+)
+
+// Hasher computes a 32-bit hash of a key, used to place the key on the
+// consistent-hash ring (or, when wrapped with Modulo, to shard directly).
+type Hasher interface {
+	Hash(key string) uint32
+}
+
+// hasherFunc adapts a plain function to the Hasher interface.
+type hasherFunc func(string) uint32
+
+func (f hasherFunc) Hash(key string) uint32 { return f(key) }
+
+// CRC32Hasher hashes keys with crc32.ChecksumIEEE. It is the default
+// Hasher used by New.
+func CRC32Hasher() Hasher {
+	return hasherFunc(func(key string) uint32 {
+		return crc32.ChecksumIEEE([]byte(key))
+	})
+}
+
+// FNVHasher hashes keys with 32-bit FNV-1a.
+func FNVHasher() Hasher {
+	return hasherFunc(func(key string) uint32 {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		return h.Sum32()
+	})
+}
+
+// moduloHasher marks a Hasher as wanting plain `hash(key) % len(servers)`
+// sharding rather than the consistent-hash ring.
+type moduloHasher struct{ Hasher }
+
+// Modulo wraps h so that the Client shards keys by simple modulo instead of
+// walking the Ketama ring. This trades the ring's "only ~1/N of keys remap
+// when a node is added or removed" property for a plain, allocation-free
+// lookup - a reasonable choice when the server list is effectively static.
+// If h is nil, CRC32Hasher is used.
+func Modulo(h Hasher) Hasher {
+	if h == nil {
+		h = CRC32Hasher()
+	}
+	return moduloHasher{h}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_CRC32Hasher(t *testing.T) {
+	t.Parallel()
+
+	h := CRC32Hasher()
+	must.Eq(t, h.Hash("mykey"), h.Hash("mykey"))
+	must.NotEq(t, h.Hash("mykey"), h.Hash("otherkey"))
+}
+
+func Test_FNVHasher(t *testing.T) {
+	t.Parallel()
+
+	h := FNVHasher()
+	must.Eq(t, h.Hash("mykey"), h.Hash("mykey"))
+	must.NotEq(t, h.Hash("mykey"), h.Hash("otherkey"))
+}
+
+func Test_Modulo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wraps the given hasher", func(t *testing.T) {
+		h := Modulo(FNVHasher())
+		m, ok := h.(moduloHasher)
+		must.True(t, ok)
+		must.Eq(t, FNVHasher().Hash("mykey"), m.Hash("mykey"))
+	})
+
+	t.Run("defaults to CRC32Hasher when nil", func(t *testing.T) {
+		h := Modulo(nil)
+		m, ok := h.(moduloHasher)
+		must.True(t, ok)
+		must.Eq(t, CRC32Hasher().Hash("mykey"), m.Hash("mykey"))
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// maxConsecutiveFailures is how many dial failures in a row eject a
+	// server from the ring.
+	maxConsecutiveFailures = 3
+
+	// ejectCooldown is how long an ejected server is left out of the ring
+	// before it is given another chance.
+	ejectCooldown = 30 * time.Second
+)
+
+// serverHealth tracks consecutive dial failures per server so that
+// persistently unreachable servers can be ejected from rotation rather
+// than slowing down every operation that happens to hash to them.
+type serverHealth struct {
+	mu           sync.Mutex
+	failures     map[string]int
+	ejectedUntil map[string]time.Time
+}
+
+func newServerHealth() *serverHealth {
+	return &serverHealth{
+		failures:     make(map[string]int),
+		ejectedUntil: make(map[string]time.Time),
+	}
+}
+
+// recordFailure notes a dial failure for addr, ejecting it once
+// maxConsecutiveFailures is reached.
+func (h *serverHealth) recordFailure(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failures[addr]++
+	if h.failures[addr] >= maxConsecutiveFailures {
+		h.ejectedUntil[addr] = time.Now().Add(ejectCooldown)
+	}
+}
+
+// recordSuccess clears addr's failure count after a successful dial.
+func (h *serverHealth) recordSuccess(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.failures, addr)
+}
+
+// ejected reports whether addr is currently ejected, reinstating it once
+// its cooldown has elapsed.
+func (h *serverHealth) ejected(addr string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	until, ok := h.ejectedUntil[addr]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(h.ejectedUntil, addr)
+		delete(h.failures, addr)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_serverHealth(t *testing.T) {
+	t.Parallel()
+
+	const addr = "10.0.0.1:11211"
+
+	t.Run("healthy by default", func(t *testing.T) {
+		h := newServerHealth()
+		must.False(t, h.ejected(addr))
+	})
+
+	t.Run("ejects after consecutive failures", func(t *testing.T) {
+		h := newServerHealth()
+
+		for i := 0; i < maxConsecutiveFailures-1; i++ {
+			h.recordFailure(addr)
+			must.False(t, h.ejected(addr))
+		}
+
+		h.recordFailure(addr)
+		must.True(t, h.ejected(addr))
+	})
+
+	t.Run("success clears the failure count", func(t *testing.T) {
+		h := newServerHealth()
+
+		for i := 0; i < maxConsecutiveFailures-1; i++ {
+			h.recordFailure(addr)
+		}
+		h.recordSuccess(addr)
+
+		h.recordFailure(addr)
+		must.False(t, h.ejected(addr))
+	})
+
+	t.Run("reinstated once the cooldown elapses", func(t *testing.T) {
+		h := newServerHealth()
+
+		for i := 0; i < maxConsecutiveFailures; i++ {
+			h.recordFailure(addr)
+		}
+		must.True(t, h.ejected(addr))
+
+		// simulate the cooldown having already elapsed, rather than
+		// sleeping out ejectCooldown in a test.
+		h.mu.Lock()
+		h.ejectedUntil[addr] = time.Now().Add(-1 * time.Second)
+		h.mu.Unlock()
+
+		must.False(t, h.ejected(addr))
+	})
+}
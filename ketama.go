@@ -0,0 +1,72 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// virtualNodesPerServer is the number of points each server gets on the
+// Ketama ring. More points give a smoother distribution of keys across
+// servers at the cost of a larger ring to build and search.
+const virtualNodesPerServer = 160
+
+// ketamaPoint is a single point on the consistent-hash ring.
+type ketamaPoint struct {
+	hash   uint32
+	server string
+}
+
+// ketamaRing is a Ketama-style consistent-hash ring: each server is mapped
+// to virtualNodesPerServer points, so that adding or removing a server only
+// remaps the keys that land on that server's points rather than reshuffling
+// everything.
+type ketamaRing struct {
+	points []ketamaPoint // sorted by hash
+}
+
+// buildKetamaRing constructs a ring over servers, placing each server's
+// virtual nodes at the hash of "<server>-<index>".
+func buildKetamaRing(servers []string) *ketamaRing {
+	points := make([]ketamaPoint, 0, len(servers)*virtualNodesPerServer)
+
+	for _, server := range servers {
+		for i := 0; i < virtualNodesPerServer; i++ {
+			label := fmt.Sprintf("%s-%d", server, i)
+			sum := md5.Sum([]byte(label))
+			points = append(points, ketamaPoint{
+				hash:   binary.LittleEndian.Uint32(sum[0:4]),
+				server: server,
+			})
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].hash < points[j].hash
+	})
+
+	return &ketamaRing{points: points}
+}
+
+// pick returns the server owning the first ring point whose hash is
+// greater than or equal to hasher.Hash(key), wrapping around to the first
+// point if key hashes past the end of the ring.
+func (r *ketamaRing) pick(key string, hasher Hasher) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	h := hasher.Hash(key)
+	idx := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if idx == len(r.points) {
+		idx = 0
+	}
+
+	return r.points[idx].server
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_buildKetamaRing(t *testing.T) {
+	t.Parallel()
+
+	servers := []string{"10.0.0.1:11211", "10.0.0.2:11211", "10.0.0.3:11211"}
+	ring := buildKetamaRing(servers)
+
+	must.Eq(t, len(servers)*virtualNodesPerServer, len(ring.points))
+
+	for i := 1; i < len(ring.points); i++ {
+		must.True(t, ring.points[i-1].hash <= ring.points[i].hash)
+	}
+}
+
+func Test_ketamaRing_pick(t *testing.T) {
+	t.Parallel()
+
+	servers := []string{"10.0.0.1:11211", "10.0.0.2:11211", "10.0.0.3:11211"}
+	ring := buildKetamaRing(servers)
+	hasher := CRC32Hasher()
+
+	t.Run("stable for the same key", func(t *testing.T) {
+		first := ring.pick("mykey", hasher)
+		for i := 0; i < 10; i++ {
+			must.Eq(t, first, ring.pick("mykey", hasher))
+		}
+	})
+
+	t.Run("always a configured server", func(t *testing.T) {
+		owned := make(map[string]bool, len(servers))
+		for _, s := range servers {
+			owned[s] = true
+		}
+
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("key-%d", i)
+			must.True(t, owned[ring.pick(key, hasher)])
+		}
+	})
+
+	t.Run("empty ring", func(t *testing.T) {
+		empty := buildKetamaRing(nil)
+		must.Eq(t, "", empty.pick("anykey", hasher))
+	})
+}
@@ -0,0 +1,26 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+// maxKeyLength is the longest key permitted by the memcached protocol.
+const maxKeyLength = 250
+
+// check validates key is a usable memcached key - non-empty, no longer than
+// maxKeyLength bytes, and free of whitespace or control characters.
+func check(key string) error {
+	switch {
+	case len(key) == 0:
+		return ErrKeyNotValid
+	case len(key) > maxKeyLength:
+		return ErrKeyNotValid
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return ErrKeyNotValid
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"context"
+	"sync"
+)
+
+// GetMultiCtx retrieves and decodes every key in keys with a single
+// pipelined round trip per shard, rather than one round trip per key.
+// Keys with no stored value are simply absent from the returned map - no
+// ErrCacheMiss. It honors ctx for cancellation and deadlines.
+func GetMultiCtx[T any](ctx context.Context, c *Client, keys []string, opts ...CallOption) (map[string]T, error) {
+	result := make(map[string]T)
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	for _, key := range keys {
+		if err := check(key); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := newCallConfig(c, opts)
+
+	byAddr := make(map[string][]string)
+	for _, key := range keys {
+		addr, err := c.pick(key)
+		if err != nil {
+			return nil, err
+		}
+		byAddr[addr] = append(byAddr[addr], key)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(chan error, len(byAddr))
+	)
+
+	for addr, shardKeys := range byAddr {
+		wg.Add(1)
+		go func(addr string, shardKeys []string) {
+			defer wg.Done()
+
+			var data map[string][]byte
+			var flags map[string]uint32
+			var err error
+			if c.binary {
+				data, flags, err = c.binaryFetchMulti(ctx, addr, shardKeys)
+			} else {
+				data, flags, err = c.textFetchMulti(ctx, addr, shardKeys)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for key, raw := range data {
+				val, uerr := unmarshalValue[T](raw, uint8(flags[key]), cfg.codec)
+				if uerr != nil {
+					errs <- uerr
+					return
+				}
+				mu.Lock()
+				result[key] = val
+				mu.Unlock()
+			}
+		}(addr, shardKeys)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	if err, ok := <-errs; ok {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetMulti retrieves and decodes every key in keys with a single pipelined
+// round trip per shard, rather than one round trip per key. Keys with no
+// stored value are simply absent from the returned map - no ErrCacheMiss.
+func GetMulti[T any](c *Client, keys []string, opts ...CallOption) (map[string]T, error) {
+	return GetMultiCtx[T](context.Background(), c, keys, opts...)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shoenig/ignore"
+	"github.com/shoenig/test/must"
+)
+
+// Test_binaryFetchMulti_trailing_miss is a regression test against a fake
+// server that mimics real memcached's miss-response framing (no key or
+// opaque echoed back): binaryFetchMulti used to wait for a response keyed
+// to the last requested key, which a miss never produces, hanging until
+// the context deadline. A bounded ctx here turns that hang into a fast,
+// explicit failure instead of blocking the test suite.
+func Test_binaryFetchMulti_trailing_miss(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	values := map[string]string{
+		"hit-1": "one",
+		"hit-2": "two",
+	}
+
+	go func() {
+		conn, aerr := ln.Accept()
+		if aerr != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		r := bufio.NewReader(conn)
+		for {
+			rawHead := make([]byte, headerSize)
+			if _, rerr := io.ReadFull(r, rawHead); rerr != nil {
+				return
+			}
+			head := decodeBinaryHeader(rawHead)
+
+			key := make([]byte, head.KeyLength)
+			if _, rerr := io.ReadFull(r, key); rerr != nil {
+				return
+			}
+
+			if head.Opcode == opNoop {
+				resp := binaryHeader{Magic: magicResponse, Opcode: opNoop}
+				if _, werr := conn.Write(resp.encode()); werr != nil {
+					return
+				}
+				continue
+			}
+
+			val, ok := values[string(key)]
+			if !ok {
+				continue // a real server sends nothing at all for a quiet miss
+			}
+
+			body := append(append([]byte{}, key...), []byte(val)...)
+			resp := binaryHeader{
+				Magic:     magicResponse,
+				Opcode:    head.Opcode,
+				KeyLength: uint16(len(key)),
+				TotalBody: uint32(len(body)),
+			}
+			if _, werr := conn.Write(resp.encode()); werr != nil {
+				return
+			}
+			if _, werr := conn.Write(body); werr != nil {
+				return
+			}
+		}
+	}()
+
+	c := New([]string{ln.Addr().String()}, UseBinaryProtocol(true))
+	defer ignore.Close(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, _, err := c.binaryFetchMulti(ctx, ln.Addr().String(), []string{"hit-1", "hit-2", "miss"})
+	must.NoError(t, err)
+	must.Eq(t, []byte("one"), data["hit-1"])
+	must.Eq(t, []byte("two"), data["hit-2"])
+
+	_, present := data["miss"]
+	must.False(t, present)
+}
@@ -0,0 +1,389 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// callConfig holds the per-call configuration built up from a chain of
+// CallOption values, shared by the Set- and Get-family functions.
+type callConfig struct {
+	ttl   time.Duration
+	codec Codec
+}
+
+// CallOption configures a single Set, SetCAS, Get, or GetCAS call.
+type CallOption func(*callConfig)
+
+// TTL overrides the Client's default expiration for a single Set call.
+// Durations of 30 days or more are sent to memcached as an absolute Unix
+// timestamp rather than a relative number of seconds - see seconds.
+func TTL(d time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.ttl = d
+	}
+}
+
+// WithCodec overrides the Client's configured Codec for a single Set or Get
+// call. The fixed-width integer types, strings, and []byte are unaffected -
+// they always use the internal fast-path codec.
+func WithCodec(codec Codec) CallOption {
+	return func(cfg *callConfig) {
+		cfg.codec = codec
+	}
+}
+
+func newCallConfig(c *Client, opts []CallOption) callConfig {
+	cfg := callConfig{ttl: c.expiration, codec: c.codec}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// store is the shared implementation behind Set, Add, and Replace - they
+// differ only in which memcached command is issued.
+func store[T any](ctx context.Context, c *Client, cmd string, op opcode, key string, val T, opts ...CallOption) error {
+	if err := check(key); err != nil {
+		return err
+	}
+
+	cfg := newCallConfig(c, opts)
+	exp, err := seconds(cfg.ttl)
+	if err != nil {
+		return err
+	}
+
+	data, tag, err := marshalValue(val, cfg.codec)
+	if err != nil {
+		return err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return err
+	}
+
+	if c.binary {
+		err = c.binaryStore(ctx, op, addr, key, uint32(tag), exp, data, 0)
+		// The binary protocol reports a failed add as "key exists" and a
+		// failed replace as "key not found"; both are ErrNotStored here, as
+		// with the text protocol.
+		if (op == opAdd && errors.Is(err, ErrExists)) || (op == opReplace && errors.Is(err, ErrCacheMiss)) {
+			return ErrNotStored
+		}
+		return err
+	}
+	return c.textStore(ctx, cmd, addr, key, uint32(tag), exp, data, 0)
+}
+
+// SetCtx stores val under key, overwriting any existing value, honoring
+// ctx for cancellation and deadlines.
+func SetCtx[T any](ctx context.Context, c *Client, key string, val T, opts ...CallOption) error {
+	return store[T](ctx, c, "set", opSet, key, val, opts...)
+}
+
+// Set stores val under key, overwriting any existing value.
+func Set[T any](c *Client, key string, val T, opts ...CallOption) error {
+	return SetCtx[T](context.Background(), c, key, val, opts...)
+}
+
+// AddCtx stores val under key only if key does not already exist,
+// returning ErrNotStored otherwise, honoring ctx for cancellation and
+// deadlines.
+func AddCtx[T any](ctx context.Context, c *Client, key string, val T, opts ...CallOption) error {
+	return store[T](ctx, c, "add", opAdd, key, val, opts...)
+}
+
+// Add stores val under key only if key does not already exist, returning
+// ErrNotStored otherwise.
+func Add[T any](c *Client, key string, val T, opts ...CallOption) error {
+	return AddCtx[T](context.Background(), c, key, val, opts...)
+}
+
+// ReplaceCtx stores val under key only if key already exists, returning
+// ErrNotStored otherwise, honoring ctx for cancellation and deadlines.
+func ReplaceCtx[T any](ctx context.Context, c *Client, key string, val T, opts ...CallOption) error {
+	return store[T](ctx, c, "replace", opReplace, key, val, opts...)
+}
+
+// Replace stores val under key only if key already exists, returning
+// ErrNotStored otherwise.
+func Replace[T any](c *Client, key string, val T, opts ...CallOption) error {
+	return ReplaceCtx[T](context.Background(), c, key, val, opts...)
+}
+
+// SetCASCtx stores val under key only if the item has not been modified
+// since it was retrieved with the given cas identifier (see GetCAS),
+// returning ErrExists if it has, honoring ctx for cancellation and
+// deadlines.
+func SetCASCtx[T any](ctx context.Context, c *Client, key string, val T, cas uint64, opts ...CallOption) error {
+	if err := check(key); err != nil {
+		return err
+	}
+
+	cfg := newCallConfig(c, opts)
+	exp, err := seconds(cfg.ttl)
+	if err != nil {
+		return err
+	}
+
+	data, tag, err := marshalValue(val, cfg.codec)
+	if err != nil {
+		return err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return err
+	}
+
+	if c.binary {
+		return c.binaryStore(ctx, opSet, addr, key, uint32(tag), exp, data, cas)
+	}
+	return c.textStore(ctx, "cas", addr, key, uint32(tag), exp, data, cas)
+}
+
+// SetCAS stores val under key only if the item has not been modified since
+// it was retrieved with the given cas identifier (see GetCAS), returning
+// ErrExists if it has.
+func SetCAS[T any](c *Client, key string, val T, cas uint64, opts ...CallOption) error {
+	return SetCASCtx[T](context.Background(), c, key, val, cas, opts...)
+}
+
+// appendPrepend is the shared implementation behind Append and Prepend.
+func appendPrepend[T any](ctx context.Context, c *Client, cmd string, op opcode, key string, val T) error {
+	if err := check(key); err != nil {
+		return err
+	}
+
+	data, tag, err := marshalValue(val, c.codec)
+	if err != nil {
+		return err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return err
+	}
+
+	if c.binary {
+		return c.binaryAppendPrepend(ctx, op, addr, key, data)
+	}
+	return c.textStore(ctx, cmd, addr, key, uint32(tag), 0, data, 0)
+}
+
+// AppendCtx adds val to the end of the existing value stored under key,
+// returning ErrNotStored if key does not exist, honoring ctx for
+// cancellation and deadlines.
+func AppendCtx[T any](ctx context.Context, c *Client, key string, val T) error {
+	return appendPrepend[T](ctx, c, "append", opAppend, key, val)
+}
+
+// Append adds val to the end of the existing value stored under key,
+// returning ErrNotStored if key does not exist.
+func Append[T any](c *Client, key string, val T) error {
+	return AppendCtx[T](context.Background(), c, key, val)
+}
+
+// PrependCtx adds val to the beginning of the existing value stored under
+// key, returning ErrNotStored if key does not exist, honoring ctx for
+// cancellation and deadlines.
+func PrependCtx[T any](ctx context.Context, c *Client, key string, val T) error {
+	return appendPrepend[T](ctx, c, "prepend", opPrepend, key, val)
+}
+
+// Prepend adds val to the beginning of the existing value stored under key,
+// returning ErrNotStored if key does not exist.
+func Prepend[T any](c *Client, key string, val T) error {
+	return PrependCtx[T](context.Background(), c, key, val)
+}
+
+// GetCtx retrieves and decodes the value stored under key, returning
+// ErrCacheMiss if key does not exist, or ErrCodecMismatch if it was stored
+// with an incompatible codec. It honors ctx for cancellation and
+// deadlines.
+func GetCtx[T any](ctx context.Context, c *Client, key string, opts ...CallOption) (T, error) {
+	val, _, err := getCAS[T](ctx, c, key, opts)
+	return val, err
+}
+
+// Get retrieves and decodes the value stored under key, returning
+// ErrCacheMiss if key does not exist, or ErrCodecMismatch if it was stored
+// with an incompatible codec.
+func Get[T any](c *Client, key string, opts ...CallOption) (T, error) {
+	return GetCtx[T](context.Background(), c, key, opts...)
+}
+
+// GetCASCtx retrieves and decodes the value stored under key along with
+// its CAS identifier, for later use with SetCAS. It honors ctx for
+// cancellation and deadlines.
+func GetCASCtx[T any](ctx context.Context, c *Client, key string, opts ...CallOption) (T, uint64, error) {
+	return getCAS[T](ctx, c, key, opts)
+}
+
+// GetCAS retrieves and decodes the value stored under key along with its
+// CAS identifier, for later use with SetCAS.
+func GetCAS[T any](c *Client, key string, opts ...CallOption) (T, uint64, error) {
+	return GetCASCtx[T](context.Background(), c, key, opts...)
+}
+
+func getCAS[T any](ctx context.Context, c *Client, key string, opts []CallOption) (T, uint64, error) {
+	var zero T
+
+	if err := check(key); err != nil {
+		return zero, 0, err
+	}
+
+	cfg := newCallConfig(c, opts)
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return zero, 0, err
+	}
+
+	var data []byte
+	var flags uint32
+	var cas uint64
+	if c.binary {
+		data, flags, cas, err = c.binaryFetch(ctx, addr, key)
+	} else {
+		data, flags, cas, err = c.textFetch(ctx, addr, key)
+	}
+	if err != nil {
+		return zero, 0, err
+	}
+
+	val, err := unmarshalValue[T](data, uint8(flags), cfg.codec)
+	if err != nil {
+		return zero, 0, err
+	}
+	return val, cas, nil
+}
+
+// DeleteCtx removes key, returning ErrCacheMiss if it does not exist,
+// honoring ctx for cancellation and deadlines.
+func DeleteCtx(ctx context.Context, c *Client, key string) error {
+	if err := check(key); err != nil {
+		return err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return err
+	}
+
+	if c.binary {
+		return c.binaryDelete(ctx, addr, key)
+	}
+	return c.textDelete(ctx, addr, key)
+}
+
+// Delete removes key, returning ErrCacheMiss if it does not exist.
+func Delete(c *Client, key string) error {
+	return DeleteCtx(context.Background(), c, key)
+}
+
+func incrDecr(ctx context.Context, c *Client, cmd string, op opcode, key string, delta uint64) (uint64, error) {
+	if err := check(key); err != nil {
+		return 0, err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.binary {
+		// exp of 0xffffffff disables auto-vivification, matching the text
+		// protocol's behavior of failing when key does not exist.
+		return c.binaryIncrDecr(ctx, op, addr, key, delta, 0, 0xffffffff)
+	}
+	return c.textIncrDecr(ctx, cmd, addr, key, delta)
+}
+
+// IncrementCtx adds delta to the (textual, unsigned) integer value stored
+// under key, returning the new value. Returns ErrCacheMiss if key does not
+// exist. It honors ctx for cancellation and deadlines.
+func IncrementCtx(ctx context.Context, c *Client, key string, delta uint64) (uint64, error) {
+	return incrDecr(ctx, c, "incr", opIncrement, key, delta)
+}
+
+// Increment adds delta to the (textual, unsigned) integer value stored
+// under key, returning the new value. Returns ErrCacheMiss if key does not
+// exist.
+func Increment(c *Client, key string, delta uint64) (uint64, error) {
+	return IncrementCtx(context.Background(), c, key, delta)
+}
+
+// DecrementCtx subtracts delta from the (textual, unsigned) integer value
+// stored under key, returning the new value. Memcached floors the result
+// at zero. Returns ErrCacheMiss if key does not exist. It honors ctx for
+// cancellation and deadlines.
+func DecrementCtx(ctx context.Context, c *Client, key string, delta uint64) (uint64, error) {
+	return incrDecr(ctx, c, "decr", opDecrement, key, delta)
+}
+
+// Decrement subtracts delta from the (textual, unsigned) integer value
+// stored under key, returning the new value. Memcached floors the result at
+// zero. Returns ErrCacheMiss if key does not exist.
+func Decrement(c *Client, key string, delta uint64) (uint64, error) {
+	return DecrementCtx(context.Background(), c, key, delta)
+}
+
+// TouchCtx updates the expiration time of key without altering its value,
+// returning ErrCacheMiss if it does not exist, honoring ctx for
+// cancellation and deadlines.
+func TouchCtx(ctx context.Context, c *Client, key string, ttl time.Duration) error {
+	if err := check(key); err != nil {
+		return err
+	}
+
+	exp, err := seconds(ttl)
+	if err != nil {
+		return err
+	}
+
+	addr, err := c.pick(key)
+	if err != nil {
+		return err
+	}
+
+	if c.binary {
+		return c.binaryTouch(ctx, addr, key, exp)
+	}
+	return c.textTouch(ctx, addr, key, exp)
+}
+
+// Touch updates the expiration time of key without altering its value,
+// returning ErrCacheMiss if it does not exist.
+func Touch(c *Client, key string, ttl time.Duration) error {
+	return TouchCtx(context.Background(), c, key, ttl)
+}
+
+// FlushAllCtx invalidates every item on every server the Client is
+// configured with, honoring ctx for cancellation and deadlines.
+func FlushAllCtx(ctx context.Context, c *Client) error {
+	for _, addr := range c.servers {
+		var err error
+		if c.binary {
+			err = c.binaryFlushAll(ctx, addr)
+		} else {
+			err = c.textFlushAll(ctx, addr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlushAll invalidates every item on every server the Client is configured
+// with.
+func FlushAll(c *Client) error {
+	return FlushAllCtx(context.Background(), c)
+}
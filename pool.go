@@ -0,0 +1,126 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// pool is a per-server cache of idle connections.
+type pool struct {
+	mu   sync.Mutex
+	idle []*connection
+}
+
+// poolFor returns (creating if necessary) the connection pool for addr.
+func (c *Client) poolFor(addr string) *pool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pools[addr]
+	if !ok {
+		p = &pool{}
+		c.pools[addr] = p
+	}
+	return p
+}
+
+// checkout returns an idle connection to addr if one is available,
+// otherwise it dials a new one, honoring ctx for cancellation.
+func (c *Client) checkout(ctx context.Context, addr string) (*connection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p := c.poolFor(addr)
+
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		conn := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return conn, nil
+	}
+	p.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		c.health.recordFailure(addr)
+		return nil, err
+	}
+	c.health.recordSuccess(addr)
+
+	return &connection{
+		nc: nc,
+		rw: bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc)),
+	}, nil
+}
+
+// checkin returns conn to addr's idle pool, unless opErr indicates the
+// connection is no longer usable, in which case it is closed instead.
+func (c *Client) checkin(addr string, conn *connection, opErr error) {
+	if opErr != nil {
+		_ = conn.nc.Close()
+		return
+	}
+
+	p := c.poolFor(addr)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.idle) >= c.maxIdleConns {
+		_ = conn.nc.Close()
+		return
+	}
+	p.idle = append(p.idle, conn)
+}
+
+// deadline picks the socket deadline for an operation: ctx's own deadline
+// if it has one, otherwise the Client's opTimeout measured from now.
+func (c *Client) deadline(ctx context.Context) time.Time {
+	if dl, ok := ctx.Deadline(); ok {
+		return dl
+	}
+	return time.Now().Add(c.opTimeout)
+}
+
+// withConn checks out a connection to addr, applies ctx's deadline (or the
+// Client's opTimeout, absent one) to its socket, passes it to fn, and
+// checks it back in (or closes it, on error) once fn returns. Cancelling
+// ctx while fn runs expires the socket deadline, so a blocked read or
+// write returns promptly with ctx's error.
+func (c *Client) withConn(ctx context.Context, addr string, fn func(conn *connection) error) error {
+	conn, err := c.checkout(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	if err = conn.nc.SetDeadline(c.deadline(ctx)); err != nil {
+		c.checkin(addr, conn, err)
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.nc.SetDeadline(time.Now())
+	})
+
+	err = fn(conn)
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if !stop() {
+		// ctx ended while fn ran and may have moved the socket deadline,
+		// so the connection is closed rather than pooled.
+		_ = conn.nc.Close()
+		return err
+	}
+	c.checkin(addr, conn, err)
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shoenig/ignore"
+	"github.com/shoenig/test/must"
+)
+
+// fakeConn is a no-op net.Conn that only tracks whether Close was called,
+// for exercising checkin without a real socket.
+type fakeConn struct {
+	net.Conn
+	closed bool
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newFakeConnection() (*connection, *fakeConn) {
+	fc := &fakeConn{}
+	return &connection{
+		nc: fc,
+		rw: bufio.NewReadWriter(bufio.NewReader(&bytes.Buffer{}), bufio.NewWriter(&bytes.Buffer{})),
+	}, fc
+}
+
+func Test_checkin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error returns the connection to the pool", func(t *testing.T) {
+		c := New([]string{"addr:1"})
+		conn, fc := newFakeConnection()
+
+		c.checkin("addr:1", conn, nil)
+
+		must.False(t, fc.closed)
+		must.Eq(t, 1, len(c.poolFor("addr:1").idle))
+	})
+
+	t.Run("a real error closes the connection", func(t *testing.T) {
+		c := New([]string{"addr:1"})
+		conn, fc := newFakeConnection()
+
+		c.checkin("addr:1", conn, errors.New("boom"))
+
+		must.True(t, fc.closed)
+		must.Eq(t, 0, len(c.poolFor("addr:1").idle))
+	})
+
+	t.Run("idle pool is bounded by SetMaxIdleConns", func(t *testing.T) {
+		c := New([]string{"addr:1"}, SetMaxIdleConns(1))
+
+		conn1, fc1 := newFakeConnection()
+		c.checkin("addr:1", conn1, nil)
+
+		conn2, fc2 := newFakeConnection()
+		c.checkin("addr:1", conn2, nil)
+
+		must.False(t, fc1.closed)
+		must.True(t, fc2.closed)
+		must.Eq(t, 1, len(c.poolFor("addr:1").idle))
+	})
+}
+
+// Test_Get_reuses_connection_on_miss is a regression test: textFetch used to
+// surface ErrCacheMiss as withConn's closure error, which made checkin treat
+// every ordinary miss as a dead connection and close it instead of pooling
+// it.
+func Test_Get_reuses_connection_on_miss(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	var accepted int32
+
+	go func() {
+		for {
+			conn, aerr := ln.Accept()
+			if aerr != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				for {
+					line, rerr := r.ReadString('\n')
+					if rerr != nil {
+						return
+					}
+					if strings.HasPrefix(line, "gets ") {
+						if _, werr := conn.Write([]byte("END\r\n")); werr != nil {
+							return
+						}
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	c := New([]string{ln.Addr().String()})
+	defer ignore.Close(c)
+
+	for i := 0; i < 5; i++ {
+		_, err := Get[string](c, "missing")
+		must.ErrorIs(t, err, ErrCacheMiss)
+	}
+
+	must.Eq(t, 1, int(atomic.LoadInt32(&accepted)))
+}
@@ -0,0 +1,317 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// textStore issues a memcached text protocol storage command (set, add,
+// replace, append, prepend, or cas) and translates the server's reply into
+// an error. STORED/NOT_STORED/EXISTS/NOT_FOUND are well-framed protocol
+// replies, not transport failures, so the translation happens after the
+// withConn closure returns rather than inside it - otherwise withConn would
+// discard a perfectly healthy connection on every ordinary miss.
+func (c *Client) textStore(ctx context.Context, cmd, addr, key string, flags uint32, exp int, data []byte, cas uint64) error {
+	var reply string
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		var line string
+		if cmd == "cas" {
+			line = fmt.Sprintf("%s %s %d %d %d %d\r\n", cmd, key, flags, exp, len(data), cas)
+		} else {
+			line = fmt.Sprintf("%s %s %d %d %d\r\n", cmd, key, flags, exp, len(data))
+		}
+
+		if _, err := conn.rw.WriteString(line); err != nil {
+			return err
+		}
+		if _, err := conn.rw.Write(data); err != nil {
+			return err
+		}
+		if _, err := conn.rw.WriteString("\r\n"); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		var rerr error
+		reply, rerr = readLine(conn.rw.Reader)
+		return rerr
+	})
+	if err != nil {
+		return err
+	}
+
+	switch reply {
+	case "STORED":
+		return nil
+	case "NOT_STORED":
+		return ErrNotStored
+	case "EXISTS":
+		return ErrExists
+	case "NOT_FOUND":
+		return ErrCacheMiss
+	default:
+		return fmt.Errorf("memc: unexpected reply %q", reply)
+	}
+}
+
+// textFetch issues a memcached "gets" text protocol command and returns the
+// stored bytes, flags, and CAS identifier for key. A miss ("END" with no
+// preceding "VALUE" line) is an ordinary, well-framed reply rather than a
+// transport failure, so it is translated to ErrCacheMiss after the withConn
+// closure returns rather than inside it - otherwise withConn would discard
+// a perfectly healthy connection on every ordinary miss.
+func (c *Client) textFetch(ctx context.Context, addr, key string) ([]byte, uint32, uint64, error) {
+	var data []byte
+	var flags uint32
+	var cas uint64
+	var miss bool
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString(fmt.Sprintf("gets %s\r\n", key)); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		header, err := readLine(conn.rw.Reader)
+		if err != nil {
+			return err
+		}
+
+		if header == "END" {
+			miss = true
+			return nil
+		}
+
+		// VALUE <key> <flags> <bytes> [<cas unique>]
+		fields := strings.Fields(header)
+		if len(fields) < 4 || fields[0] != "VALUE" {
+			return fmt.Errorf("memc: unexpected reply %q", header)
+		}
+
+		flags64, _ := strconv.ParseUint(fields[2], 10, 32)
+		flags = uint32(flags64)
+
+		size, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return fmt.Errorf("memc: unexpected reply %q", header)
+		}
+
+		if len(fields) >= 5 {
+			cas, _ = strconv.ParseUint(fields[4], 10, 64)
+		}
+
+		buf := make([]byte, size+2) // + trailing "\r\n"
+		if _, err = io.ReadFull(conn.rw.Reader, buf); err != nil {
+			return err
+		}
+		data = buf[:size]
+
+		_, err = readLine(conn.rw.Reader) // consume "END"
+		return err
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if miss {
+		return nil, 0, 0, ErrCacheMiss
+	}
+
+	return data, flags, cas, nil
+}
+
+// textFetchMulti issues a single pipelined memcached text protocol "get"
+// command for all of keys and returns the stored bytes and flags for each
+// key that was found. Keys with no stored value are simply absent from the
+// returned maps.
+func (c *Client) textFetchMulti(ctx context.Context, addr string, keys []string) (map[string][]byte, map[string]uint32, error) {
+	data := make(map[string][]byte)
+	flags := make(map[string]uint32)
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString("get " + strings.Join(keys, " ") + "\r\n"); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		for {
+			header, err := readLine(conn.rw.Reader)
+			if err != nil {
+				return err
+			}
+			if header == "END" {
+				return nil
+			}
+
+			// VALUE <key> <flags> <bytes>
+			fields := strings.Fields(header)
+			if len(fields) < 4 || fields[0] != "VALUE" {
+				return fmt.Errorf("memc: unexpected reply %q", header)
+			}
+
+			key := fields[1]
+			flags64, _ := strconv.ParseUint(fields[2], 10, 32)
+			size, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return fmt.Errorf("memc: unexpected reply %q", header)
+			}
+
+			buf := make([]byte, size+2) // + trailing "\r\n"
+			if _, err = io.ReadFull(conn.rw.Reader, buf); err != nil {
+				return err
+			}
+
+			data[key] = buf[:size]
+			flags[key] = uint32(flags64)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, flags, nil
+}
+
+// textDelete issues a memcached text protocol "delete" command. DELETED and
+// NOT_FOUND are translated to an error after the withConn closure returns,
+// since both are ordinary, well-framed replies rather than transport
+// failures.
+func (c *Client) textDelete(ctx context.Context, addr, key string) error {
+	var reply string
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString(fmt.Sprintf("delete %s\r\n", key)); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		var rerr error
+		reply, rerr = readLine(conn.rw.Reader)
+		return rerr
+	})
+	if err != nil {
+		return err
+	}
+
+	switch reply {
+	case "DELETED":
+		return nil
+	case "NOT_FOUND":
+		return ErrCacheMiss
+	default:
+		return fmt.Errorf("memc: unexpected reply %q", reply)
+	}
+}
+
+// textIncrDecr issues a memcached text protocol "incr"/"decr" command.
+// NOT_FOUND is translated to an error after the withConn closure returns,
+// since it is an ordinary, well-framed reply rather than a transport
+// failure.
+func (c *Client) textIncrDecr(ctx context.Context, cmd, addr, key string, delta uint64) (uint64, error) {
+	var reply string
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString(fmt.Sprintf("%s %s %d\r\n", cmd, key, delta)); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		var rerr error
+		reply, rerr = readLine(conn.rw.Reader)
+		return rerr
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if reply == "NOT_FOUND" {
+		return 0, ErrCacheMiss
+	}
+
+	n, err := strconv.ParseUint(reply, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("memc: unexpected reply %q", reply)
+	}
+	return n, nil
+}
+
+// textTouch issues a memcached text protocol "touch" command. TOUCHED and
+// NOT_FOUND are translated to an error after the withConn closure returns,
+// since both are ordinary, well-framed replies rather than transport
+// failures.
+func (c *Client) textTouch(ctx context.Context, addr, key string, exp int) error {
+	var reply string
+
+	err := c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString(fmt.Sprintf("touch %s %d\r\n", key, exp)); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		var rerr error
+		reply, rerr = readLine(conn.rw.Reader)
+		return rerr
+	})
+	if err != nil {
+		return err
+	}
+
+	switch reply {
+	case "TOUCHED":
+		return nil
+	case "NOT_FOUND":
+		return ErrCacheMiss
+	default:
+		return fmt.Errorf("memc: unexpected reply %q", reply)
+	}
+}
+
+// textFlushAll issues a memcached text protocol "flush_all" command.
+func (c *Client) textFlushAll(ctx context.Context, addr string) error {
+	return c.withConn(ctx, addr, func(conn *connection) error {
+		if _, err := conn.rw.WriteString("flush_all\r\n"); err != nil {
+			return err
+		}
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+
+		reply, err := readLine(conn.rw.Reader)
+		if err != nil {
+			return err
+		}
+		if reply != "OK" {
+			return fmt.Errorf("memc: unexpected reply %q", reply)
+		}
+		return nil
+	})
+}
+
+// readLine reads a single CRLF terminated line, with the trailing CRLF
+// trimmed off.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+import "time"
+
+// thirtyDays is the memcached protocol boundary at which an exptime value
+// switches meaning from "seconds from now" to "absolute unix timestamp".
+const thirtyDays = 60 * 60 * 24 * 30
+
+// seconds converts ttl into the integer exptime memcached expects, erroring
+// if ttl is not a whole number of seconds.
+//
+// Per the memcached protocol, an exptime greater than thirtyDays is
+// interpreted by the server as an absolute Unix timestamp rather than a
+// number of seconds from now, so ttl values of a month or more are encoded
+// as time.Now().Add(ttl).Unix() instead of ttl/time.Second.
+func seconds(ttl time.Duration) (int, error) {
+	if ttl == 0 {
+		return 0, nil
+	}
+
+	if ttl%time.Second != 0 {
+		return 0, ErrExpiration
+	}
+
+	s := int(ttl / time.Second)
+	if s >= thirtyDays {
+		return int(time.Now().Add(ttl).Unix()), nil
+	}
+
+	return s, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The Noxide Project Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package memc
+
+// isFastPath reports whether v is one of the fixed-width integer types, a
+// string, or a []byte - all of which always use the internal fast-path
+// codec (encode/decode), regardless of the Client's configured Codec. This
+// keeps the wire format for strings and []byte stable no matter which
+// Codec is configured, matching what every version of this client has
+// written for those types; only other types are actually routed through
+// Codec.Marshal/Unmarshal.
+func isFastPath(v any) bool {
+	switch v.(type) {
+	case int8, uint8, int16, uint16, int32, uint32, int64, uint64, int, uint, string, []byte:
+		return true
+	default:
+		return false
+	}
+}
+
+// marshalValue encodes val for storage, returning the bytes to send along
+// with the codec tag to record in the item's flags.
+func marshalValue(val any, codec Codec) ([]byte, uint8, error) {
+	if isFastPath(val) {
+		data, err := encode(val)
+		return data, tagInternal, err
+	}
+
+	data, err := codec.Marshal(val)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, codecTag(codec), nil
+}
+
+// unmarshalValue decodes data into a T, checking that tag (the codec tag
+// recorded in the item's flags) is compatible with either the internal
+// fast-path codec or codec, as appropriate for T.
+func unmarshalValue[T any](data []byte, tag uint8, codec Codec) (T, error) {
+	var zero T
+
+	if isFastPath(zero) {
+		if tag != tagInternal {
+			return zero, ErrCodecMismatch
+		}
+		return decode[T](data)
+	}
+
+	if tag != codecTag(codec) {
+		return zero, ErrCodecMismatch
+	}
+
+	var out T
+	if err := codec.Unmarshal(data, &out); err != nil {
+		return zero, err
+	}
+	return out, nil
+}